@@ -0,0 +1,174 @@
+package gomodel
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"strings"
+)
+
+// bulkType is the single sql type bulkCache needs, analogous to qsType: a
+// bulk insert's cache key already folds in the model's table, fields and
+// chunk size, so there's no need to split across the package's predefined
+// per-model sql types.
+const bulkType uint = 0
+
+// Placeholder limits that keep a single multi-row INSERT under a driver's
+// cap. mysqlBulkPlaceholders is mysql's hard limit of 65535 placeholders
+// per statement; other dialects are kept to a conservative placeholder
+// budget regardless of their actual (usually higher) limit.
+const (
+	mysqlBulkPlaceholders   = 65535
+	defaultBulkPlaceholders = 1000
+)
+
+// BulkInsert inserts models with a single multi-row
+// "INSERT ... VALUES (?,?),(?,?),..." statement per chunk instead of one
+// ArgsInsert round-trip per row, splitting models into chunks that stay
+// under the driver's placeholder limit. Every model must share the same
+// table and be inserting the same fields.
+//
+// With resType RES_ID it returns the first row's inserted id, and requires
+// a dialect whose AutoIncrement is true; RES_ROWS returns the total rows
+// affected across every chunk and works with any dialect.
+//
+// The prepared statement is cached per (table, fields, chunk row count), so
+// calling BulkInsert with many distinct batch sizes accumulates one cached
+// statement per distinct size, callers that can choose their batch size
+// get the most reuse by keeping it constant.
+func (db *DB) BulkInsert(models []Model, fields uint64, resType ResultType) (int64, error) {
+	if len(models) == 0 {
+		return 0, nil
+	}
+
+	if db.dialect == nil {
+		return 0, fmt.Errorf("gomodel: BulkInsert requires a Dialect, none resolved for this DB")
+	}
+
+	if resType == RES_ID && !db.dialect.AutoIncrement() {
+		return 0, fmt.Errorf("gomodel: BulkInsert with RES_ID requires an auto-increment dialect, %T relies on a RETURNING clause which BulkInsert does not support", db.dialect)
+	}
+
+	chunk := db.bulkChunkRows(NumFields(fields))
+
+	var (
+		total    int64
+		first    int64
+		firstSet bool
+	)
+
+	for len(models) > 0 {
+		n := chunk
+		if n > len(models) {
+			n = len(models)
+		}
+
+		id, err := db.bulkInsertChunk(models[:n], fields, resType)
+		if err != nil {
+			return 0, err
+		}
+
+		if !firstSet {
+			first, firstSet = id, true
+		}
+		total += id
+
+		models = models[n:]
+	}
+
+	if resType == RES_ID {
+		return first, nil
+	}
+
+	return total, nil
+}
+
+// bulkChunkRows caps a chunk's row count to stay under the driver's
+// placeholder limit, scaling down as fieldsPerRow grows so wide models
+// don't blow the limit with fewer rows than a narrow model would
+func (db *DB) bulkChunkRows(fieldsPerRow int) int {
+	if fieldsPerRow < 1 {
+		fieldsPerRow = 1
+	}
+
+	limit := defaultBulkPlaceholders
+	if _, is := db.dialect.(mysqlDialect); is {
+		limit = mysqlBulkPlaceholders
+	}
+
+	if rows := limit / fieldsPerRow; rows > 0 {
+		return rows
+	}
+
+	return 1
+}
+
+func (db *DB) bulkInsertChunk(models []Model, fields uint64, resType ResultType) (int64, error) {
+	model := models[0]
+	t := db.Table(model)
+	cols := columnsForFields(model, fields)
+
+	args := make([]interface{}, 0, len(cols)*len(models))
+	for _, m := range models {
+		args = append(args, FieldVals(m, fields)...)
+	}
+
+	id := bulkID(t.Name, fields, len(models))
+	sql_, cached, stmt, err := db.bulkCache.StmtById(db.DB, bulkType, id, func() string {
+		return db.dialect.Rewrite(buildBulkInsertSQL(db.dialect, t.Name, cols, len(models)))
+	})
+
+	return ExecContext(context.Background(), sql_, cached, stmt, err, resType, args...)
+}
+
+// columnsForFields lists the column names fields selects, in bit order,
+// the same fields-bit-to-struct-field-index correspondence Cacher's doc
+// comment describes for the predefined sql type ids
+func columnsForFields(model Model, fields uint64) []string {
+	v := reflect.ValueOf(model)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	typ := v.Type()
+
+	cols := make([]string, 0, NumFields(fields))
+	for i := 0; i < typ.NumField(); i++ {
+		if fields&(1<<uint(i)) == 0 {
+			continue
+		}
+
+		cols = append(cols, dbColumnName(typ.Field(i)))
+	}
+
+	return cols
+}
+
+// buildBulkInsertSQL renders a single INSERT with one "(?,?,...)" group per
+// row, using '?' placeholders regardless of dialect, the caller rewrites
+// them through Dialect.Rewrite before preparing the statement
+func buildBulkInsertSQL(dialect Dialect, table string, cols []string, rows int) string {
+	quoted := make([]string, len(cols))
+	for i, c := range cols {
+		quoted[i] = dialect.Quote(c)
+	}
+
+	group := "(" + placeholders(len(cols)) + ")"
+	groups := make([]string, rows)
+	for i := range groups {
+		groups[i] = group
+	}
+
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES %s",
+		dialect.Quote(table), strings.Join(quoted, ", "), strings.Join(groups, ","))
+}
+
+// bulkID hashes the shape of a bulk insert statement, two calls with the
+// same table, fields and row count always produce identical SQL text, so
+// the hash is safe to use as a prepared-statement cache key
+func bulkID(table string, fields uint64, rows int) uint {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s|%d|%d", table, fields, rows)
+
+	return uint(h.Sum64())
+}
@@ -1,9 +1,11 @@
 package gomodel
 
 import (
+	"context"
 	"database/sql"
-	"log"
+	"sync"
 	"sync/atomic"
+	"time"
 )
 
 type (
@@ -28,48 +30,33 @@ type (
 	// if custom is necessary, call cache.ExtendType(cache.Types()+1) to make
 	// a new type, the sql id is bring your owns, also you can still use the standard
 	// FieldIdentity(fields, whereFields) if possible
+	//
+	// mu guards cache so a Cacher can be shared by concurrent callers (every
+	// Table and DB-level cache is) without racing on the underlying maps.
 	Cacher struct {
+		mu    sync.Mutex
 		cache []map[uint]cacheItem // [type]map[id]{sql, stmt}
 	}
-
-	SQLPrinter func(string, ...interface{})
 )
 
 const (
-	// These are five predefined sql types
+	// These are the predefined sql types
 	INSERT uint = iota
 	DELETE
 	UPDATE
 	SELECT_LIMIT
 	SELECT_ONE
 	SELECT_ALL
+	COUNT
+	INCRBY
 
 	defaultTypeEnd
 )
 
-var (
-	// Types defines the default sql types count, it's default applied to all
-	// models.
-	// Change it before register any models.
-	Types                 = defaultTypeEnd
-	sqlPrinter SQLPrinter = func(string, ...interface{}) {}
-)
-
-func (p SQLPrinter) Print(fromcache bool, sql string) {
-	p("Cached: %t, SQL: %s\n", fromcache, sql)
-}
-
-// SQLPrint enable sql print for each operation
-func SQLPrint(enable bool, printer func(formart string, v ...interface{})) {
-	if !enable {
-		return
-	}
-
-	sqlPrinter = printer
-	if sqlPrinter == nil {
-		sqlPrinter = log.Printf
-	}
-}
+// Types defines the default sql types count, it's default applied to all
+// models.
+// Change it before register any models.
+var Types = defaultTypeEnd
 
 // NewID create a id generator used for StmtById, normally, one ID is enough,
 // it's safety used for all models
@@ -119,6 +106,9 @@ func NewCacher(types uint) Cacher {
 // //b.go
 // newType2 := c.ExtendType(c.Types()+1)
 func (c *Cacher) ExtendType(typ uint) uint {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if l := uint(len(c.cache)); typ > l {
 		cache := make([]map[uint]cacheItem, typ)
 		copy(cache, c.cache)
@@ -134,36 +124,55 @@ func (c *Cacher) ExtendType(typ uint) uint {
 	return typ - 1
 }
 
-// StmtById search a prepared statement for given sql type by id, if not found,
-// create with the creator, and prepared the sql to a statement, cache it, then
-// return
-func (c *Cacher) StmtById(p Preparer, typ, id uint, create func() string) (*sql.Stmt, error) {
-	if item, has := c.cache[typ][id]; has {
-		sqlPrinter.Print(true, item.sql)
+// StmtById searches a prepared statement for given sql type by id, if not
+// found, creates it with the creator, prepares the sql to a statement and
+// caches it. It returns the statement's sql text and whether it was already
+// cached alongside the statement itself, so a caller that goes on to
+// execute the statement can log the execution with its real sql and args
+// rather than StmtById's own args-less preparation.
+//
+// A cache hit logs nothing here: nothing ran, so there's no duration or
+// args to report; the caller's execution is what's worth logging.
+//
+// c.mu guards every cache read/write below, so concurrent StmtById calls
+// for a not-yet-cached id on the same Cacher (e.g. two goroutines racing
+// to build the same Table's statement, or two BulkInsert calls of
+// different shapes) never race on the underlying map.
+func (c *Cacher) StmtById(p Preparer, typ, id uint, create func() string) (sql_ string, cached bool, stmt *sql.Stmt, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-		return item.stmt, nil
+	if item, has := c.cache[typ][id]; has {
+		return item.sql, true, item.stmt, nil
 	}
 
-	sql_ := create()
-	sqlPrinter.Print(false, sql_)
+	sql_ = create()
 
-	stmt, err := p.Prepare(sql_)
+	start := time.Now()
+	stmt, err = p.Prepare(sql_)
+	logQuery(context.Background(), sql_, nil, start, err, false)
 	if err != nil {
-		return nil, err
+		return "", false, nil, err
 	}
 
 	c.cache[typ][id] = cacheItem{sql: sql_, stmt: stmt}
 
-	return stmt, nil
+	return sql_, false, stmt, nil
 }
 
 // Types return the sql types count of current Cacher
 func (c *Cacher) Types() uint {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	return uint(len(c.cache))
 }
 
 // GetStmt get sql and statement from cacher, if not found, "" and nil was returned
 func (c *Cacher) GetStmt(typ, id uint) (string, *sql.Stmt) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	item, has := c.cache[typ][id]
 	if !has {
 		return "", nil
@@ -179,20 +188,27 @@ func (c *Cacher) SetStmt(p Preparer, typ uint, id uint, sql string) (*sql.Stmt,
 		return nil, err
 	}
 
+	c.mu.Lock()
 	c.cache[typ][id] = cacheItem{
 		sql:  sql,
 		stmt: stmt,
 	}
+	c.mu.Unlock()
 
 	return stmt, nil
 }
 
 func (c *Cacher) PrepareStmt(p Preparer, typ, id uint) (string, *sql.Stmt, error) {
+	c.mu.Lock()
 	item, has := c.cache[typ][id]
+	c.mu.Unlock()
 	if !has {
 		return "", nil, nil
 	}
 
+	start := time.Now()
 	stmt, err := p.Prepare(item.sql)
+	logQuery(context.Background(), item.sql, nil, start, err, true)
+
 	return item.sql, stmt, err
 }
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/yonglehou/gomodel"
+)
+
+// Migrations is the project's ordered migration set. A project vendoring
+// this command fills it in (directly, or from an init() in a sibling file)
+// before building the binary; gomodel has no way to discover Go-authored
+// Up/Down steps on its own the way a SQL-file-based migrator could.
+var Migrations []gomodel.Migration
+
+func main() {
+	driver := flag.String("driver", "", "database/sql driver name (mysql, postgres, sqlite3, ...)")
+	dsn := flag.String("dsn", "", "data source name passed to the driver")
+	flag.Parse()
+
+	if err := run(*driver, *dsn, flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(driver, dsn string, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("gomodel: usage: gomodel -driver=... -dsn=... migrate up|down|status")
+	}
+	if args[0] != "migrate" {
+		return fmt.Errorf("gomodel: unknown command %q, expected \"migrate\"", args[0])
+	}
+
+	db, err := gomodel.Open(driver, dsn, 2, 10)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return runMigrate(db, Migrations, args[1:])
+}
+
+// runMigrate dispatches the "gomodel migrate up/down/status" subcommands
+// against db, applying/rolling back/reporting migrations in the order
+// they're declared in migrations.
+func runMigrate(db *gomodel.DB, migrations []gomodel.Migration, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("gomodel migrate: missing subcommand, expected one of up, down, status")
+	}
+
+	switch args[0] {
+	case "up":
+		return db.Migrate(migrations, true)
+	case "down":
+		return db.Migrate(migrations, false)
+	case "status":
+		states, err := db.MigrationStatus(migrations)
+		if err != nil {
+			return err
+		}
+
+		printMigrationStatus(states)
+
+		return nil
+	default:
+		return fmt.Errorf("gomodel migrate: unknown subcommand %q, expected one of up, down, status", args[0])
+	}
+}
+
+func printMigrationStatus(states []gomodel.MigrationState) {
+	for _, s := range states {
+		mark := "pending"
+		if s.Applied {
+			mark = "applied"
+		}
+
+		fmt.Printf("%-8s %6d  %s\n", mark, s.Version, s.Name)
+	}
+}
@@ -0,0 +1,227 @@
+package gomodel
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// ContextStmt is an optional interface for Stmt, implemented by any Stmt
+// whose driver call supports cancellation and deadlines, which in practice
+// is every Stmt returned by Table/Cacher since they all wrap *sql.Stmt
+// under the hood.
+type ContextStmt interface {
+	Stmt
+	ExecContext(ctx context.Context, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, args ...interface{}) (*sql.Rows, error)
+}
+
+// ExecContext is the context-aware counterpart of Exec. sql_ and cached are
+// only used for logging: they identify what stmt actually runs, since a
+// Stmt carries neither its source text nor whether it came from a
+// prepared-statement cache.
+func ExecContext(ctx context.Context, sql_ string, cached bool, stmt Stmt, err error, typ ResultType, args ...interface{}) (int64, error) {
+	if err != nil {
+		return 0, err
+	}
+
+	start := time.Now()
+
+	var res sql.Result
+	if cs, is := stmt.(ContextStmt); is {
+		res, err = cs.ExecContext(ctx, args...)
+	} else {
+		res, err = stmt.Exec(args...)
+	}
+	logQuery(ctx, sql_, args, start, err, cached)
+
+	return ResolveResult(res, err, typ)
+}
+
+// UpdateContext is the context-aware counterpart of Update
+func UpdateContext(ctx context.Context, sql_ string, cached bool, stmt Stmt, err error, args ...interface{}) (int64, error) {
+	return ExecContext(ctx, sql_, cached, stmt, err, RES_ROWS, args...)
+}
+
+// QueryContext is the context-aware counterpart of Query. sql_ and cached
+// serve the same logging purpose as in ExecContext.
+func QueryContext(ctx context.Context, sql_ string, cached bool, stmt Stmt, err error, args ...interface{}) Scanner {
+	if err != nil {
+		return Scanner{Error: err}
+	}
+
+	start := time.Now()
+
+	var (
+		rows *sql.Rows
+	)
+	if cs, is := stmt.(ContextStmt); is {
+		rows, err = cs.QueryContext(ctx, args...)
+	} else {
+		rows, err = stmt.Query(args...)
+	}
+	logQuery(ctx, sql_, args, start, err, cached)
+
+	return Scanner{
+		Error: err,
+		Rows:  rows,
+		Stmt:  stmt,
+	}
+}
+
+func (db *DB) InsertContext(ctx context.Context, model Model, fields uint64, resType ResultType) (int64, error) {
+	return db.ArgsInsertContext(ctx, model, fields, resType, FieldVals(model, fields)...)
+}
+
+func (db *DB) ArgsInsertContext(ctx context.Context, model Model, fields uint64, resType ResultType, args ...interface{}) (int64, error) {
+	if resType == RES_ID && db.dialect != nil && !db.dialect.AutoIncrement() {
+		return 0, fmt.Errorf("gomodel: Insert with RES_ID requires an auto-increment dialect, %T relies on a RETURNING clause which this path does not support", db.dialect)
+	}
+
+	sql_, cached, stmt, err := db.Table(model).StmtInsert(db.DB, fields)
+
+	return ExecContext(ctx, sql_, cached, stmt, err, resType, args...)
+}
+
+func (db *DB) UpdateContext(ctx context.Context, model Model, fields, whereFields uint64) (int64, error) {
+	c1, c2 := NumFields(fields), NumFields(whereFields)
+	args := make([]interface{}, c1+c2)
+	model.Vals(fields, args)
+	model.Vals(whereFields, args[c1:])
+
+	return db.ArgsUpdateContext(ctx, model, fields, whereFields, args...)
+}
+
+func (db *DB) ArgsUpdateContext(ctx context.Context, model Model, fields, whereFields uint64, args ...interface{}) (int64, error) {
+	sql_, cached, stmt, err := db.Table(model).StmtUpdate(db.DB, fields, whereFields)
+
+	return UpdateContext(ctx, sql_, cached, stmt, err, args...)
+}
+
+func (db *DB) DeleteContext(ctx context.Context, model Model, whereFields uint64) (int64, error) {
+	return db.ArgsDeleteContext(ctx, model, whereFields, FieldVals(model, whereFields)...)
+}
+
+func (db *DB) ArgsDeleteContext(ctx context.Context, model Model, whereFields uint64, args ...interface{}) (int64, error) {
+	sql_, cached, stmt, err := db.Table(model).StmtDelete(db.DB, whereFields)
+
+	return UpdateContext(ctx, sql_, cached, stmt, err, args...)
+}
+
+// OneContext select one row from database
+func (db *DB) OneContext(ctx context.Context, model Model, fields, whereFields uint64) error {
+	sql_, cached, stmt, err := db.Table(model).StmtOne(db.DB, fields, whereFields)
+	scanner := QueryContext(ctx, sql_, cached, stmt, err, FieldVals(model, whereFields)...)
+
+	return scanner.One(FieldPtrs(model, fields)...)
+}
+
+func (db *DB) LimitContext(ctx context.Context, store Store, model Model, fields, whereFields uint64, start, count int) error {
+	args := FieldVals(model, whereFields, start, count)
+
+	return db.ArgsLimitContext(ctx, store, model, fields, whereFields, args...)
+}
+
+// The last two arguments must be "start" and "count" of limition with type "int"
+func (db *DB) ArgsLimitContext(ctx context.Context, store Store, model Model, fields, whereFields uint64, args ...interface{}) error {
+	t := db.Table(model)
+	bindStore(store, t)
+
+	sql_, cached, stmt, err := t.StmtLimit(db.DB, fields, whereFields)
+	scanner := QueryContext(ctx, sql_, cached, stmt, err, args...)
+
+	return scanner.Limit(store, args[len(args)-1].(int))
+}
+
+func (db *DB) AllContext(ctx context.Context, store Store, model Model, fields, whereFields uint64) error {
+	return db.ArgsAllContext(ctx, store, model, fields, whereFields, FieldVals(model, whereFields)...)
+}
+
+// ArgsAllContext select all rows matching whereFields, args are bound to the
+// where placeholders only, there is no limit
+func (db *DB) ArgsAllContext(ctx context.Context, store Store, model Model, fields, whereFields uint64, args ...interface{}) error {
+	t := db.Table(model)
+	bindStore(store, t)
+
+	sql_, cached, stmt, err := t.StmtAll(db.DB, fields, whereFields)
+	scanner := QueryContext(ctx, sql_, cached, stmt, err, args...)
+
+	return scanner.All(store, db.InitialModels)
+}
+
+// CountContext return count of rows for model, arguments was extracted from Model
+func (db *DB) CountContext(ctx context.Context, model Model, whereFields uint64) (count int64, err error) {
+	return db.ArgsCountContext(ctx, model, whereFields, FieldVals(model, whereFields)...)
+}
+
+// ArgsCountContext return count of rows for model use custome arguments
+func (db *DB) ArgsCountContext(ctx context.Context, model Model, whereFields uint64, args ...interface{}) (count int64, err error) {
+	t := db.Table(model)
+
+	sql_, cached, stmt, err := t.StmtCount(db.DB, whereFields)
+	scanner := QueryContext(ctx, sql_, cached, stmt, err, args...)
+
+	err = scanner.One(&count)
+
+	return
+}
+
+func (db *DB) IncrByContext(ctx context.Context, model Model, field, whereFields uint64, count int) (int64, error) {
+	args := make([]interface{}, NumFields(whereFields)+1)
+	args[0] = count
+	model.Vals(whereFields, args[1:])
+
+	return db.ArgsIncrByContext(ctx, model, field, whereFields, args...)
+}
+
+func (db *DB) ArgsIncrByContext(ctx context.Context, model Model, field, whereFields uint64, args ...interface{}) (int64, error) {
+	sql_, cached, stmt, err := db.Table(model).StmtIncrBy(db.DB, field, whereFields)
+
+	return UpdateContext(ctx, sql_, cached, stmt, err, args...)
+}
+
+// ExecUpdateContext execute a update operation, return resolved result
+func (db *DB) ExecUpdateContext(ctx context.Context, sql string, args ...interface{}) (int64, error) {
+	return db.ExecContext(ctx, sql, RES_ROWS, args...)
+}
+
+// ExecContext execute a update operation, return resolved result
+func (db *DB) ExecContext(ctx context.Context, sql string, resType ResultType, args ...interface{}) (int64, error) {
+	start := time.Now()
+	res, err := db.DB.ExecContext(ctx, sql, args...)
+	logQuery(ctx, sql, args, start, err, false)
+
+	return ResolveResult(res, err, resType)
+}
+
+func (db *DB) ExecByIdContext(ctx context.Context, idsql IdSql, resTyp ResultType, args ...interface{}) (int64, error) {
+	stmt, err := db.StmtById(db, idsql)
+
+	return ExecContext(ctx, fmt.Sprintf("idsql:%v", idsql), false, stmt, err, resTyp, args...)
+}
+
+func (db *DB) UpdateByIdContext(ctx context.Context, idsql IdSql, args ...interface{}) (int64, error) {
+	return db.ExecByIdContext(ctx, idsql, RES_ROWS, args...)
+}
+
+func (db *DB) QueryByIdContext(ctx context.Context, idsql IdSql, args ...interface{}) Scanner {
+	stmt, err := db.StmtById(db, idsql)
+
+	return QueryContext(ctx, fmt.Sprintf("idsql:%v", idsql), false, stmt, err, args...)
+}
+
+// BeginContext starts a transaction with opts, cancelling/rolling back if
+// ctx is done before Tx.Close commits or rolls it back
+func (db *DB) BeginContext(ctx context.Context, opts *sql.TxOptions) (Tx, error) {
+	tx, err := db.DB.BeginTx(ctx, opts)
+	if err != nil {
+		return emptyTX, err
+	}
+
+	return Tx{
+		Tx:        tx,
+		db:        db,
+		isSuccess: true,
+	}, nil
+}
@@ -1,7 +1,11 @@
 // Package database is a library help for interact with database by model
 package gomodel
 
-import "database/sql"
+import (
+	"context"
+	"database/sql"
+	"sync"
+)
 
 type (
 	// DB holds database connection, all typeinfos, and sql cache
@@ -13,6 +17,39 @@ type (
 
 		// initial models count for 'All'
 		InitialModels int
+
+		// qsCache holds compiled QuerySet statements, keyed by a hash of
+		// their condition shape so repeated calls with the same shape but
+		// different bound values reuse the same prepared statement
+		qsCache Cacher
+
+		// qsMu guards compiling and caching a new QuerySet shape, so two
+		// goroutines building different not-yet-cached shapes at once don't
+		// race on qsCache's underlying map
+		qsMu sync.Mutex
+
+		// bulkCache holds compiled BulkInsert statements, keyed by a hash of
+		// the model's table, fields and chunk size, so repeated calls with
+		// the same shape reuse the same prepared statement
+		bulkCache Cacher
+
+		// namedCache holds compiled Named/NamedStmt statements, keyed by a
+		// hash of their literal template text
+		namedCache Cacher
+
+		// namedParams holds the :name bindings, in template order, each
+		// namedCache entry's statement expects; Cacher itself only keeps
+		// sql text and the prepared statement, not this side table
+		namedParams map[uint][]string
+
+		// namedMu guards compiling and caching a new Named/NamedStmt
+		// template, so namedCache and namedParams are always updated
+		// together rather than as two separately-racing maps
+		namedMu sync.Mutex
+
+		// dialect generates the SQL this DB's driver expects, set from the
+		// driver name given to Connect/Register
+		dialect Dialect
 	}
 )
 
@@ -29,6 +66,10 @@ func NewDB() *DB {
 	return &DB{
 		tables:        make(map[string]*Table),
 		InitialModels: 10,
+		qsCache:       NewCacher(1),
+		bulkCache:     NewCacher(1),
+		namedCache:    NewCacher(1),
+		namedParams:   make(map[uint][]string),
 	}
 }
 
@@ -39,14 +80,29 @@ func (db *DB) Connect(driver, dsn string, maxIdle, maxOpen int) error {
 		return err
 	}
 
+	dialect, err := dialectFor(driver)
+	if err != nil {
+		db_.Close()
+
+		return err
+	}
+
 	db_.SetMaxIdleConns(maxIdle)
 	db_.SetMaxOpenConns(maxOpen)
 	db.DB = db_
 	db.cache = newCache()
+	db.dialect = dialect
 
 	return nil
 }
 
+// Dialect return the Dialect this DB generates SQL for, it's nil until
+// Connect/Register succeeds, which requires a Dialect already registered
+// for the driver it's called with
+func (db *DB) Dialect() Dialect {
+	return db.dialect
+}
+
 // register save table of model
 func (db *DB) register(model Model, table string) *Table {
 	t := parse(model, db)
@@ -67,143 +123,103 @@ func (db *DB) Table(model Model) *Table {
 }
 
 func (db *DB) Insert(model Model, fields uint64, resType ResultType) (int64, error) {
-	return db.ArgsInsert(model, fields, resType, FieldVals(model, fields)...)
+	return db.InsertContext(context.Background(), model, fields, resType)
 }
 
 func (db *DB) ArgsInsert(model Model, fields uint64, resType ResultType, args ...interface{}) (int64, error) {
-	stmt, err := db.Table(model).StmtInsert(db.DB, fields)
-
-	return Exec(stmt, err, resType, args...)
+	return db.ArgsInsertContext(context.Background(), model, fields, resType, args...)
 }
 
 func (db *DB) Update(model Model, fields, whereFields uint64) (int64, error) {
-	c1, c2 := NumFields(fields), NumFields(whereFields)
-	args := make([]interface{}, c1+c2)
-	model.Vals(fields, args)
-	model.Vals(whereFields, args[c1:])
-
-	return db.ArgsUpdate(model, fields, whereFields, args...)
+	return db.UpdateContext(context.Background(), model, fields, whereFields)
 }
 
 func (db *DB) ArgsUpdate(model Model, fields, whereFields uint64, args ...interface{}) (int64, error) {
-	stmt, err := db.Table(model).StmtUpdate(db.DB, fields, whereFields)
-
-	return Update(stmt, err, args...)
+	return db.ArgsUpdateContext(context.Background(), model, fields, whereFields, args...)
 }
 
 func (db *DB) Delete(model Model, whereFields uint64) (int64, error) {
-	return db.ArgsDelete(model, whereFields, FieldVals(model, whereFields)...)
+	return db.DeleteContext(context.Background(), model, whereFields)
 }
 
 func (db *DB) ArgsDelete(model Model, whereFields uint64, args ...interface{}) (int64, error) {
-	stmt, err := db.Table(model).StmtDelete(db.DB, whereFields)
-
-	return Update(stmt, err, args...)
+	return db.ArgsDeleteContext(context.Background(), model, whereFields, args...)
 }
 
 // One select one row from database
 func (db *DB) One(model Model, fields, whereFields uint64) error {
-	stmt, err := db.Table(model).StmtOne(db.DB, fields, whereFields)
-	scanner := Query(stmt, err, FieldVals(model, whereFields)...)
-
-	return scanner.One(FieldPtrs(model, fields)...)
+	return db.OneContext(context.Background(), model, fields, whereFields)
 }
 
 func (db *DB) Limit(store Store, model Model, fields, whereFields uint64, start, count int) error {
-	args := FieldVals(model, whereFields, start, count)
-
-	return db.ArgsLimit(store, model, fields, whereFields, args...)
+	return db.LimitContext(context.Background(), store, model, fields, whereFields, start, count)
 }
 
 // The last two arguments must be "start" and "count" of limition with type "int"
 func (db *DB) ArgsLimit(store Store, model Model, fields, whereFields uint64, args ...interface{}) error {
-	stmt, err := db.Table(model).StmtLimit(db.DB, fields, whereFields)
-	scanner := Query(stmt, err, args...)
-
-	return scanner.Limit(store, args[len(args)-1].(int))
+	return db.ArgsLimitContext(context.Background(), store, model, fields, whereFields, args...)
 }
 
 func (db *DB) All(store Store, model Model, fields, whereFields uint64) error {
-	return db.ArgsAll(store, model, fields, whereFields, FieldVals(model, whereFields)...)
+	return db.AllContext(context.Background(), store, model, fields, whereFields)
 }
 
 // ArgsAll select all  the last two argument must be "start" and "count"
 func (db *DB) ArgsAll(store Store, model Model, fields, whereFields uint64, args ...interface{}) error {
-	stmt, err := db.Table(model).StmtAll(db.DB, fields, whereFields)
-	scanner := Query(stmt, err, args...)
+	return db.ArgsAllContext(context.Background(), store, model, fields, whereFields, args...)
+}
 
-	return scanner.All(store, db.InitialModels)
+// bindStore gives a Store the chance to resolve its column mapping against
+// t before it's handed to a Scanner, used by stores such as StructStore
+// whose Ptrs depend on knowing the Model's Table
+func bindStore(store Store, t *Table) {
+	if b, is := store.(TableBinder); is {
+		b.bindTable(t)
+	}
 }
 
 // Count return count of rows for model, arguments was extracted from Model
 func (db *DB) Count(model Model, whereFields uint64) (count int64, err error) {
-	return db.ArgsCount(model, whereFields, FieldVals(model, whereFields)...)
+	return db.CountContext(context.Background(), model, whereFields)
 }
 
 // ArgsCount return count of rows for model use custome arguments
 func (db *DB) ArgsCount(model Model, whereFields uint64, args ...interface{}) (count int64, err error) {
-	t := db.Table(model)
-
-	stmt, err := t.StmtCount(db.DB, whereFields)
-	scanner := Query(stmt, err, args...)
-
-	err = scanner.One(&count)
-
-	return
+	return db.ArgsCountContext(context.Background(), model, whereFields, args...)
 }
 
 func (db *DB) IncrBy(model Model, field, whereFields uint64, count int) (int64, error) {
-	args := make([]interface{}, NumFields(whereFields)+1)
-	args[0] = count
-	model.Vals(whereFields, args[1:])
-
-	return db.ArgsIncrBy(model, field, whereFields, args...)
+	return db.IncrByContext(context.Background(), model, field, whereFields, count)
 }
 
 func (db *DB) ArgsIncrBy(model Model, field, whereFields uint64, args ...interface{}) (int64, error) {
-	stmt, err := db.Table(model).StmtIncrBy(db.DB, field, whereFields)
-
-	return Update(stmt, err, args...)
+	return db.ArgsIncrByContext(context.Background(), model, field, whereFields, args...)
 }
 
 // ExecUpdate execute a update operation, return resolved result
 func (db *DB) ExecUpdate(sql string, args ...interface{}) (int64, error) {
-	return db.Exec(sql, RES_ROWS, args...)
+	return db.ExecUpdateContext(context.Background(), sql, args...)
 }
 
 // Exec execute a update operation, return resolved result
 func (db *DB) Exec(sql string, resType ResultType, args ...interface{}) (int64, error) {
-	res, err := db.DB.Exec(sql, args...)
-
-	return ResolveResult(res, err, resType)
+	return db.ExecContext(context.Background(), sql, resType, args...)
 }
 
 func (db *DB) ExecById(idsql IdSql, resTyp ResultType, args ...interface{}) (int64, error) {
-	stmt, err := db.StmtById(db, idsql)
-
-	return Exec(stmt, err, resTyp, args...)
+	return db.ExecByIdContext(context.Background(), idsql, resTyp, args...)
 }
 
 func (db *DB) UpdateById(idsql IdSql, args ...interface{}) (int64, error) {
-	return db.ExecById(idsql, RES_ROWS, args...)
+	return db.UpdateByIdContext(context.Background(), idsql, args...)
 }
 
 func (db *DB) QueryById(idsql IdSql, args ...interface{}) Scanner {
-	stmt, err := db.StmtById(db, idsql)
-
-	return Query(stmt, err, args...)
+	return db.QueryByIdContext(context.Background(), idsql, args...)
 }
 
 var emptyTX = Tx{}
 
 func (db *DB) Begin() (Tx, error) {
-	tx, err := db.DB.Begin()
-	if err != nil {
-		return emptyTX, err
-	}
-
-	return Tx{
-		Tx: tx,
-		db: db,
-	}, nil
+	return db.BeginContext(context.Background(), nil)
 }
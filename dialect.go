@@ -0,0 +1,184 @@
+package gomodel
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Dialect abstracts the SQL differences between database backends, so a
+// single model definition generates correct SQL no matter which backend a
+// DB was registered with. Table's statement builders route placeholder
+// rewriting, quoting, LIMIT/OFFSET, and auto-increment retrieval through
+// the DB's Dialect instead of hard-coding mysql syntax.
+type Dialect interface {
+	// Rewrite replaces the standard '?' placeholders in sql, in order, with
+	// this dialect's bind-parameter syntax, mysql and sqlite keep '?' as-is,
+	// postgres rewrites them to "$1", "$2", ...
+	Rewrite(sql string) string
+
+	// Quote quotes a table or column identifier
+	Quote(identifier string) string
+
+	// Limit renders the LIMIT/OFFSET clause for the given offset and count
+	Limit(offset, count int) string
+
+	// Returning renders the clause an INSERT statement needs to retrieve
+	// column's generated value, it's empty for dialects that rely on
+	// AutoIncrement instead
+	Returning(column string) string
+
+	// AutoIncrement reports whether an inserted row's auto-increment value
+	// is obtained through sql.Result.LastInsertId, dialects that return
+	// false require a Returning clause and must scan the generated value
+	// from the query result instead
+	AutoIncrement() bool
+
+	// Columns returns a query and its args that yield one row per existing
+	// column of table, with the column name as its only selected value, and
+	// zero rows if table doesn't exist. SyncSchema uses an empty result to
+	// tell apart CREATE TABLE from ALTER TABLE.
+	Columns(table string) (sql string, args []interface{})
+
+	// ColumnType renders the DDL column type SyncSchema should use for a Go
+	// struct field of the given type
+	ColumnType(typ reflect.Type) string
+}
+
+type (
+	mysqlDialect    struct{}
+	postgresDialect struct{}
+	sqlite3Dialect  struct{}
+)
+
+func (mysqlDialect) Rewrite(sql string) string { return sql }
+
+func (mysqlDialect) Quote(identifier string) string { return "`" + identifier + "`" }
+
+func (mysqlDialect) Limit(offset, count int) string {
+	return fmt.Sprintf("LIMIT %d,%d", offset, count)
+}
+
+func (mysqlDialect) Returning(string) string { return "" }
+
+func (mysqlDialect) AutoIncrement() bool { return true }
+
+func (mysqlDialect) Columns(table string) (string, []interface{}) {
+	return "SELECT column_name FROM information_schema.columns WHERE table_schema = DATABASE() AND table_name = ?",
+		[]interface{}{table}
+}
+
+func (mysqlDialect) ColumnType(typ reflect.Type) string { return genericColumnType(typ) }
+
+func (postgresDialect) Rewrite(sql string) string {
+	var b strings.Builder
+	n := 0
+
+	for i := 0; i < len(sql); i++ {
+		if sql[i] == '?' {
+			n++
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(n))
+		} else {
+			b.WriteByte(sql[i])
+		}
+	}
+
+	return b.String()
+}
+
+func (postgresDialect) Quote(identifier string) string { return `"` + identifier + `"` }
+
+func (postgresDialect) Limit(offset, count int) string {
+	return fmt.Sprintf("LIMIT %d OFFSET %d", count, offset)
+}
+
+func (postgresDialect) Returning(column string) string { return "RETURNING " + column }
+
+func (postgresDialect) AutoIncrement() bool { return false }
+
+func (postgresDialect) Columns(table string) (string, []interface{}) {
+	return "SELECT column_name FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = $1",
+		[]interface{}{table}
+}
+
+func (postgresDialect) ColumnType(typ reflect.Type) string {
+	switch t := genericColumnType(typ); t {
+	case "DOUBLE":
+		return "DOUBLE PRECISION"
+	case "DATETIME":
+		return "TIMESTAMP"
+	default:
+		return t
+	}
+}
+
+func (sqlite3Dialect) Rewrite(sql string) string { return sql }
+
+func (sqlite3Dialect) Quote(identifier string) string { return `"` + identifier + `"` }
+
+func (sqlite3Dialect) Limit(offset, count int) string {
+	return fmt.Sprintf("LIMIT %d OFFSET %d", count, offset)
+}
+
+func (sqlite3Dialect) Returning(string) string { return "" }
+
+func (sqlite3Dialect) AutoIncrement() bool { return true }
+
+func (sqlite3Dialect) Columns(table string) (string, []interface{}) {
+	return fmt.Sprintf("SELECT name FROM pragma_table_info(%s)", sqlite3Dialect{}.Quote(table)), nil
+}
+
+func (sqlite3Dialect) ColumnType(typ reflect.Type) string { return genericColumnType(typ) }
+
+// timeType is compared against directly since time.Time is the one
+// non-primitive struct genericColumnType needs to special-case
+var timeType = reflect.TypeOf(time.Time{})
+
+// genericColumnType maps a Go field type to the DDL column type shared by
+// every builtin dialect, callers that need a dialect-specific override
+// (e.g. postgres' DOUBLE PRECISION, TIMESTAMP) adjust its result rather
+// than duplicating the switch
+func genericColumnType(typ reflect.Type) string {
+	if typ == timeType {
+		return "DATETIME"
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		return "BOOLEAN"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "INTEGER"
+	case reflect.Float32, reflect.Float64:
+		return "DOUBLE"
+	default:
+		return "TEXT"
+	}
+}
+
+// dialects holds the Dialect registered for each database/sql driver name
+var dialects = map[string]Dialect{
+	"mysql":    mysqlDialect{},
+	"postgres": postgresDialect{},
+	"sqlite3":  sqlite3Dialect{},
+}
+
+// RegisterDialect makes a custom Dialect available under driver, call it
+// before Register or Open use that driver name. It also lets callers
+// override one of the three builtin dialects.
+func RegisterDialect(driver string, d Dialect) {
+	dialects[driver] = d
+}
+
+// dialectFor looks up the Dialect registered for driver
+func dialectFor(driver string) (Dialect, error) {
+	d, has := dialects[driver]
+	if !has {
+		return nil, fmt.Errorf("gomodel: no dialect registered for driver %q", driver)
+	}
+
+	return d, nil
+}
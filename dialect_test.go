@@ -0,0 +1,108 @@
+package gomodel
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPostgresRewrite(t *testing.T) {
+	got := postgresDialect{}.Rewrite("SELECT * FROM t WHERE a = ? AND b = ?")
+	want := "SELECT * FROM t WHERE a = $1 AND b = $2"
+	if got != want {
+		t.Fatalf("Rewrite() = %q, want %q", got, want)
+	}
+}
+
+func TestMysqlSqlite3RewriteIsNoop(t *testing.T) {
+	const sql_ = "SELECT * FROM t WHERE a = ?"
+
+	if got := (mysqlDialect{}).Rewrite(sql_); got != sql_ {
+		t.Fatalf("mysqlDialect.Rewrite() = %q, want unchanged %q", got, sql_)
+	}
+	if got := (sqlite3Dialect{}).Rewrite(sql_); got != sql_ {
+		t.Fatalf("sqlite3Dialect.Rewrite() = %q, want unchanged %q", got, sql_)
+	}
+}
+
+func TestQuote(t *testing.T) {
+	if got := (mysqlDialect{}).Quote("user"); got != "`user`" {
+		t.Fatalf("mysqlDialect.Quote() = %q", got)
+	}
+	if got := (postgresDialect{}).Quote("user"); got != `"user"` {
+		t.Fatalf("postgresDialect.Quote() = %q", got)
+	}
+	if got := (sqlite3Dialect{}).Quote("user"); got != `"user"` {
+		t.Fatalf("sqlite3Dialect.Quote() = %q", got)
+	}
+}
+
+func TestLimit(t *testing.T) {
+	if got := (mysqlDialect{}).Limit(5, 10); got != "LIMIT 5,10" {
+		t.Fatalf("mysqlDialect.Limit() = %q", got)
+	}
+	if got := (postgresDialect{}).Limit(5, 10); got != "LIMIT 10 OFFSET 5" {
+		t.Fatalf("postgresDialect.Limit() = %q", got)
+	}
+	if got := (sqlite3Dialect{}).Limit(5, 10); got != "LIMIT 10 OFFSET 5" {
+		t.Fatalf("sqlite3Dialect.Limit() = %q", got)
+	}
+}
+
+func TestAutoIncrementAndReturning(t *testing.T) {
+	if !(mysqlDialect{}).AutoIncrement() || !(sqlite3Dialect{}).AutoIncrement() {
+		t.Fatal("mysql and sqlite3 are expected to be auto-increment dialects")
+	}
+	if (postgresDialect{}).AutoIncrement() {
+		t.Fatal("postgres is not an auto-increment dialect")
+	}
+	if got := (postgresDialect{}).Returning("id"); got != "RETURNING id" {
+		t.Fatalf("postgresDialect.Returning() = %q", got)
+	}
+	if got := (mysqlDialect{}).Returning("id"); got != "" {
+		t.Fatalf("mysqlDialect.Returning() = %q, want empty", got)
+	}
+}
+
+func TestGenericColumnType(t *testing.T) {
+	cases := []struct {
+		val  interface{}
+		want string
+	}{
+		{true, "BOOLEAN"},
+		{int64(0), "INTEGER"},
+		{uint8(0), "INTEGER"},
+		{float64(0), "DOUBLE"},
+		{"", "TEXT"},
+		{time.Time{}, "DATETIME"},
+	}
+
+	for _, c := range cases {
+		got := genericColumnType(reflect.TypeOf(c.val))
+		if got != c.want {
+			t.Errorf("genericColumnType(%T) = %q, want %q", c.val, got, c.want)
+		}
+	}
+}
+
+func TestPostgresColumnTypeOverrides(t *testing.T) {
+	d := postgresDialect{}
+	if got := d.ColumnType(reflect.TypeOf(float64(0))); got != "DOUBLE PRECISION" {
+		t.Fatalf("postgres ColumnType(float64) = %q", got)
+	}
+	if got := d.ColumnType(reflect.TypeOf(time.Time{})); got != "TIMESTAMP" {
+		t.Fatalf("postgres ColumnType(time.Time) = %q", got)
+	}
+	if got := d.ColumnType(reflect.TypeOf(int64(0))); got != "INTEGER" {
+		t.Fatalf("postgres ColumnType(int64) = %q, want unaffected INTEGER", got)
+	}
+}
+
+func TestDialectFor(t *testing.T) {
+	if _, err := dialectFor("mysql"); err != nil {
+		t.Fatalf("dialectFor(mysql): %v", err)
+	}
+	if _, err := dialectFor("no-such-driver"); err == nil {
+		t.Fatal("dialectFor(unregistered driver) should error")
+	}
+}
@@ -0,0 +1,76 @@
+package gomodel
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+type (
+	// Logger receives every SQL statement gomodel executes or prepares,
+	// along with its bound args, how long the driver call took, whether it
+	// was served from the prepared-statement cache, and any error
+	// returned. It replaces the previous SQLPrinter, which could only
+	// print the SQL text and a cache-hit flag.
+	Logger interface {
+		LogQuery(ctx context.Context, sql string, args []interface{}, duration time.Duration, err error, cached bool)
+	}
+
+	// Redactor receives the bound args of a query right before they reach
+	// a Logger, and returns a copy safe to log, e.g. masking password or
+	// token columns. It's only applied to logged args, never to the args
+	// bound to the actual statement.
+	Redactor func(args []interface{}) []interface{}
+
+	// stdLogger is the default Logger, it writes through the standard
+	// library logger.
+	stdLogger struct {
+		*log.Logger
+	}
+
+	discardLogger struct{}
+)
+
+func (l stdLogger) LogQuery(_ context.Context, sql string, args []interface{}, duration time.Duration, err error, cached bool) {
+	if err != nil {
+		l.Printf("cached=%t duration=%s sql=%q args=%v error=%s", cached, duration, sql, args, err)
+
+		return
+	}
+
+	l.Printf("cached=%t duration=%s sql=%q args=%v", cached, duration, sql, args)
+}
+
+func (discardLogger) LogQuery(context.Context, string, []interface{}, time.Duration, error, bool) {}
+
+var (
+	queryLogger Logger = stdLogger{log.New(log.Writer(), "[gomodel] ", log.LstdFlags)}
+	redactArgs  Redactor
+)
+
+// SetLogger installs logger as the destination for every SQL statement
+// gomodel executes or prepares, pass nil to discard logging entirely.
+func SetLogger(logger Logger) {
+	if logger == nil {
+		logger = discardLogger{}
+	}
+
+	queryLogger = logger
+}
+
+// SetRedactor installs redactor to sanitize bound args before they reach
+// the Logger, pass nil to log args unredacted.
+func SetRedactor(redactor Redactor) {
+	redactArgs = redactor
+}
+
+// logQuery reports a finished SQL statement to the installed Logger,
+// start is when the driver call began so the duration can be measured here
+// rather than at every call site.
+func logQuery(ctx context.Context, sql string, args []interface{}, start time.Time, err error, cached bool) {
+	if redactArgs != nil {
+		args = redactArgs(args)
+	}
+
+	queryLogger.LogQuery(ctx, sql, args, time.Since(start), err, cached)
+}
@@ -0,0 +1,320 @@
+package gomodel
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+type (
+	// Migration is one versioned schema change. Version must be unique and
+	// increasing across a set of migrations passed to Migrate, it's what
+	// gets recorded in the gomodel_migrations table to decide whether the
+	// migration still needs applying.
+	Migration struct {
+		Version int64
+		Name    string
+		Up      func(Tx) error
+		Down    func(Tx) error
+	}
+
+	// MigrationState reports whether one Migration has already been applied,
+	// as returned by MigrationStatus for a "migrate status" CLI subcommand.
+	MigrationState struct {
+		Migration
+		Applied bool
+	}
+)
+
+// migrationsTable stores which of a Migration slice's versions Migrate has
+// already applied
+const migrationsTable = "gomodel_migrations"
+
+// SyncSchema creates, for each of models, whichever of its table or columns
+// the connected database doesn't have yet. It never alters or drops an
+// existing column, so it's safe to call on every startup as models gain
+// new fields; use Migrate for changes SyncSchema can't express, such as
+// renames, drops, or data backfills.
+func (db *DB) SyncSchema(models ...Model) error {
+	dialect := db.dialect
+	if dialect == nil {
+		return fmt.Errorf("gomodel: SyncSchema requires a Dialect, none resolved for this DB")
+	}
+
+	for _, model := range models {
+		if err := db.syncTable(dialect, model); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (db *DB) syncTable(dialect Dialect, model Model) error {
+	t := db.Table(model)
+	cols := t.Columns()
+	types := columnTypes(model, dialect)
+
+	existing, err := db.existingColumns(dialect, t.Name)
+	if err != nil {
+		return err
+	}
+
+	if existing == nil {
+		return db.createTable(dialect, t.Name, cols, types)
+	}
+
+	has := make(map[string]bool, len(existing))
+	for _, col := range existing {
+		has[col] = true
+	}
+
+	for _, col := range cols {
+		if has[col] {
+			continue
+		}
+
+		sql_ := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", dialect.Quote(t.Name), dialect.Quote(col), types[col])
+		if _, err := db.Exec(sql_, RES_ROWS); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (db *DB) createTable(dialect Dialect, table string, cols []string, types map[string]string) error {
+	defs := make([]string, len(cols))
+	for i, col := range cols {
+		defs[i] = fmt.Sprintf("%s %s", dialect.Quote(col), types[col])
+	}
+
+	sql_ := fmt.Sprintf("CREATE TABLE %s (%s)", dialect.Quote(table), strings.Join(defs, ", "))
+	_, err := db.Exec(sql_, RES_ROWS)
+
+	return err
+}
+
+// existingColumns returns the columns table currently has, nil if the
+// table doesn't exist at all. A SQL table always has at least one column,
+// so an empty result is an unambiguous "doesn't exist" signal.
+func (db *DB) existingColumns(dialect Dialect, table string) ([]string, error) {
+	colSQL, colArgs := dialect.Columns(table)
+	start := time.Now()
+	rows, err := db.DB.Query(colSQL, colArgs...)
+	logQuery(context.Background(), colSQL, colArgs, start, err, false)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cols []string
+	for rows.Next() {
+		var col string
+		if err := rows.Scan(&col); err != nil {
+			return nil, err
+		}
+
+		cols = append(cols, col)
+	}
+
+	return cols, rows.Err()
+}
+
+// columnTypes maps each of model's columns to its DDL type for dialect,
+// resolved by reflecting over the model struct's fields the same way
+// fieldMapping matches a result column back to a struct field
+func columnTypes(model Model, dialect Dialect) map[string]string {
+	v := reflect.ValueOf(model)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	typ := v.Type()
+
+	types := make(map[string]string, typ.NumField())
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		types[dbColumnName(field)] = dialect.ColumnType(field.Type)
+	}
+
+	return types
+}
+
+// ensureMigrationsTable creates migrationsTable if it doesn't exist yet
+func (db *DB) ensureMigrationsTable() error {
+	dialect := db.dialect
+	if dialect == nil {
+		return fmt.Errorf("gomodel: Migrate requires a Dialect, none resolved for this DB")
+	}
+
+	existing, err := db.existingColumns(dialect, migrationsTable)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return nil
+	}
+
+	sql_ := fmt.Sprintf("CREATE TABLE %s (%s %s, %s %s)",
+		dialect.Quote(migrationsTable),
+		dialect.Quote("version"), dialect.ColumnType(reflect.TypeOf(int64(0))),
+		dialect.Quote("name"), dialect.ColumnType(reflect.TypeOf("")))
+	_, err = db.Exec(sql_, RES_ROWS)
+
+	return err
+}
+
+// AppliedMigrations returns the versions already recorded in
+// migrationsTable, oldest first
+func (db *DB) AppliedMigrations() ([]int64, error) {
+	if err := db.ensureMigrationsTable(); err != nil {
+		return nil, err
+	}
+
+	dialect := db.dialect
+	sql_ := fmt.Sprintf("SELECT %s FROM %s ORDER BY %s",
+		dialect.Quote("version"), dialect.Quote(migrationsTable), dialect.Quote("version"))
+
+	start := time.Now()
+	rows, err := db.DB.Query(sql_)
+	logQuery(context.Background(), sql_, nil, start, err, false)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var versions []int64
+	for rows.Next() {
+		var v int64
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+
+		versions = append(versions, v)
+	}
+
+	return versions, rows.Err()
+}
+
+// Migrate applies migrations' Up steps in order when up is true, or rolls
+// back their Down steps in reverse order when up is false. Going up skips any
+// version already recorded in migrationsTable; going down skips any version
+// not recorded there, so every applied migration is rolled back. It backs the "gomodel
+// migrate up"/"gomodel migrate down" CLI subcommands.
+func (db *DB) Migrate(migrations []Migration, up bool) error {
+	if up {
+		return db.migrateUp(migrations)
+	}
+
+	return db.migrateDown(migrations)
+}
+
+func (db *DB) migrateUp(migrations []Migration) error {
+	done, err := db.appliedSet()
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if done[m.Version] {
+			continue
+		}
+
+		if err := db.applyMigration(m, m.Up, true); err != nil {
+			return fmt.Errorf("gomodel: migration %d (%s): %w", m.Version, m.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (db *DB) migrateDown(migrations []Migration) error {
+	done, err := db.appliedSet()
+	if err != nil {
+		return err
+	}
+
+	for i := len(migrations) - 1; i >= 0; i-- {
+		m := migrations[i]
+		if !done[m.Version] {
+			continue
+		}
+
+		if err := db.applyMigration(m, m.Down, false); err != nil {
+			return fmt.Errorf("gomodel: migration %d (%s): %w", m.Version, m.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (db *DB) appliedSet() (map[int64]bool, error) {
+	applied, err := db.AppliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	done := make(map[int64]bool, len(applied))
+	for _, v := range applied {
+		done[v] = true
+	}
+
+	return done, nil
+}
+
+// applyMigration runs step inside its own transaction, then records or
+// removes the migration's version in migrationsTable as part of the same
+// transaction, so a crash between the two never leaves the tracked state
+// out of sync with the schema.
+func (db *DB) applyMigration(m Migration, step func(Tx) error, up bool) (err error) {
+	if step == nil {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		tx.Success(err == nil)
+		if cerr := tx.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	if err = step(tx); err != nil {
+		return err
+	}
+
+	dialect := db.dialect
+	if up {
+		sql_ := dialect.Rewrite(fmt.Sprintf("INSERT INTO %s (%s, %s) VALUES (?, ?)",
+			dialect.Quote(migrationsTable), dialect.Quote("version"), dialect.Quote("name")))
+		_, err = tx.Exec(sql_, RES_ROWS, m.Version, m.Name)
+	} else {
+		sql_ := dialect.Rewrite(fmt.Sprintf("DELETE FROM %s WHERE %s = ?",
+			dialect.Quote(migrationsTable), dialect.Quote("version")))
+		_, err = tx.Exec(sql_, RES_ROWS, m.Version)
+	}
+
+	return err
+}
+
+// MigrationStatus reports, for each of migrations in order, whether it has
+// already been applied. It backs the "gomodel migrate status" CLI
+// subcommand.
+func (db *DB) MigrationStatus(migrations []Migration) ([]MigrationState, error) {
+	done, err := db.appliedSet()
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]MigrationState, len(migrations))
+	for i, m := range migrations {
+		states[i] = MigrationState{Migration: m, Applied: done[m.Version]}
+	}
+
+	return states, nil
+}
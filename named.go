@@ -0,0 +1,287 @@
+package gomodel
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+)
+
+// namedType is the single sql type namedCache needs, analogous to qsType
+// and bulkType: a template's cache key is already its literal text, so
+// there's no need to split across the package's predefined sql types.
+const namedType uint = 0
+
+// NamedStmt is a compiled, prepared handle for a template given to
+// Named/NamedStmt, reusable across calls with different named args.
+type NamedStmt struct {
+	sql_   string
+	stmt   *sql.Stmt
+	params []string
+}
+
+// Named compiles template (see NamedStmt for the syntax it accepts), binds
+// args by name, and runs it as a query, returning the matched rows. A
+// template run often enough to want its prepared handle kept around
+// explicitly should go through NamedStmt once instead.
+//
+// Example:
+//   rows := db.Named(
+//       "SELECT {User.id} FROM {User} WHERE {User:name} = :name AND {User:age} >= :min_age",
+//       map[string]interface{}{"name": "bob", "min_age": 18},
+//   )
+func (db *DB) Named(template string, args map[string]interface{}) Scanner {
+	ns, err := db.NamedStmt(template)
+	if err != nil {
+		return Scanner{Error: err}
+	}
+
+	return ns.Query(args)
+}
+
+// NamedStmt compiles and prepares template, returning a reusable handle. A
+// template combines two substitutions:
+//
+//   {Model}          the table name of a model already registered via some
+//                    prior db.Table/db.Insert/... call
+//   {Model:Field}    Field's column name
+//   {Model.Field}    Field's column name, qualified with the table name
+//   :name            a named bind parameter, rewritten to the dialect's
+//                    positional placeholder syntax
+//
+// Compilation is cached by template's literal text, so repeated calls with
+// the same template only pay the parse cost once.
+func (db *DB) NamedStmt(template string) (*NamedStmt, error) {
+	dialect := db.dialect
+	if dialect == nil {
+		return nil, fmt.Errorf("gomodel: Named requires a Dialect, none resolved for this DB")
+	}
+
+	id := namedID(template)
+
+	db.namedMu.Lock()
+	defer db.namedMu.Unlock()
+
+	if cachedSQL, stmt := db.namedCache.GetStmt(namedType, id); stmt != nil {
+		return &NamedStmt{sql_: cachedSQL, stmt: stmt, params: db.namedParams[id]}, nil
+	}
+
+	sql_, params, err := compileNamedTemplate(db, dialect, template)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	stmt, err := db.namedCache.SetStmt(db.DB, namedType, id, sql_)
+	logQuery(context.Background(), sql_, nil, start, err, false)
+	if err != nil {
+		return nil, err
+	}
+
+	db.namedParams[id] = params
+
+	return &NamedStmt{sql_: sql_, stmt: stmt, params: params}, nil
+}
+
+// Query runs ns with args bound by name, returning the matched rows
+func (ns *NamedStmt) Query(args map[string]interface{}) Scanner {
+	return ns.QueryContext(context.Background(), args)
+}
+
+// QueryContext is the context-aware counterpart of Query
+func (ns *NamedStmt) QueryContext(ctx context.Context, args map[string]interface{}) Scanner {
+	return QueryContext(ctx, ns.sql_, true, ns.stmt, nil, ns.bind(args)...)
+}
+
+// Exec runs ns as a write (INSERT/UPDATE/DELETE) with args bound by name
+func (ns *NamedStmt) Exec(resType ResultType, args map[string]interface{}) (int64, error) {
+	return ns.ExecContext(context.Background(), resType, args)
+}
+
+// ExecContext is the context-aware counterpart of Exec
+func (ns *NamedStmt) ExecContext(ctx context.Context, resType ResultType, args map[string]interface{}) (int64, error) {
+	return ExecContext(ctx, ns.sql_, true, ns.stmt, nil, resType, ns.bind(args)...)
+}
+
+// bind resolves ns.params against args, in the order the template's :name
+// placeholders appeared
+func (ns *NamedStmt) bind(args map[string]interface{}) []interface{} {
+	vals := make([]interface{}, len(ns.params))
+	for i, name := range ns.params {
+		vals[i] = args[name]
+	}
+
+	return vals
+}
+
+// compileNamedTemplate expands {Model}/{Model:Field}/{Model.Field}
+// references against db's registered tables, and rewrites :name bindings
+// to dialect's positional placeholders, returning the bindings in the
+// order they appeared so args can be reassembled positionally at call time
+func compileNamedTemplate(db *DB, dialect Dialect, template string) (string, []string, error) {
+	const (
+		stateInit = iota
+		stateModel
+		stateField
+		stateParam
+	)
+
+	var (
+		sql_    strings.Builder
+		model   strings.Builder
+		field   strings.Builder
+		param   strings.Builder
+		params  []string
+		table   *Table
+		qualify bool
+		state   = stateInit
+	)
+
+	for i := 0; i < len(template); i++ {
+		c := template[i]
+
+		switch state {
+		case stateInit:
+			switch {
+			case c == '{':
+				state = stateModel
+				qualify = false
+				model.Reset()
+			case c == ':' && i+1 < len(template) && template[i+1] == ':':
+				// a literal "::" (e.g. postgres' ":id::int" cast syntax),
+				// not the start of a :name binding
+				sql_.WriteString("::")
+				i++
+			case c == ':':
+				state = stateParam
+				param.Reset()
+			default:
+				sql_.WriteByte(c)
+			}
+
+		case stateParam:
+			if isIdentByte(c) {
+				param.WriteByte(c)
+
+				continue
+			}
+
+			if param.Len() == 0 {
+				return "", nil, fmt.Errorf("gomodel: empty named parameter in template %q", template)
+			}
+
+			params = append(params, param.String())
+			sql_.WriteByte('?')
+			state = stateInit
+			i--
+
+		case stateModel:
+			switch c {
+			case '}':
+				t, err := namedTable(db, model.String())
+				if err != nil {
+					return "", nil, err
+				}
+
+				sql_.WriteString(dialect.Quote(t.Name))
+				state = stateInit
+			case '.', ':':
+				t, err := namedTable(db, model.String())
+				if err != nil {
+					return "", nil, err
+				}
+
+				qualify = c == '.'
+				table = t
+				field.Reset()
+				state = stateField
+			default:
+				model.WriteByte(c)
+			}
+
+		case stateField:
+			switch c {
+			case ',', '}':
+				if err := writeNamedField(&sql_, dialect, table, qualify, field.String()); err != nil {
+					return "", nil, err
+				}
+
+				field.Reset()
+
+				if c == '}' {
+					state = stateInit
+				} else {
+					sql_.WriteByte(',')
+				}
+			case ' ':
+				// skip, keeps "Field, Field" and "Field,Field" equivalent
+			default:
+				field.WriteByte(c)
+			}
+		}
+	}
+
+	switch state {
+	case stateParam:
+		if param.Len() == 0 {
+			return "", nil, fmt.Errorf("gomodel: empty named parameter in template %q", template)
+		}
+
+		params = append(params, param.String())
+		sql_.WriteByte('?')
+	case stateModel, stateField:
+		return "", nil, fmt.Errorf("gomodel: unterminated {...} reference in template %q", template)
+	}
+
+	return dialect.Rewrite(sql_.String()), params, nil
+}
+
+func namedTable(db *DB, name string) (*Table, error) {
+	t, has := db.tables[name]
+	if !has {
+		return nil, fmt.Errorf("gomodel: model %q isn't registered", name)
+	}
+
+	return t, nil
+}
+
+func writeNamedField(sql_ *strings.Builder, dialect Dialect, table *Table, qualify bool, field string) error {
+	found := false
+	for _, col := range table.Columns() {
+		if col == field {
+			found = true
+
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("gomodel: field %q of model %q not found", field, table.Name)
+	}
+
+	if qualify {
+		sql_.WriteString(dialect.Quote(table.Name))
+		sql_.WriteByte('.')
+	}
+	sql_.WriteString(dialect.Quote(field))
+
+	return nil
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' ||
+		(c >= 'a' && c <= 'z') ||
+		(c >= 'A' && c <= 'Z') ||
+		(c >= '0' && c <= '9')
+}
+
+// namedID hashes template's literal text, two calls with the same template
+// always produce identical compiled SQL, so the hash is safe to use as a
+// cache key for the compiled statement and its param order
+func namedID(template string) uint {
+	h := fnv.New64a()
+	h.Write([]byte(template))
+
+	return uint(h.Sum64())
+}
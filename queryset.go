@@ -0,0 +1,360 @@
+package gomodel
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+type (
+	// lookup describes how a "field__suffix" expression is rendered to SQL,
+	// and how many placeholders it consumes from the bound value.
+	//   numArgs == 0 means the lookup takes no bound value (e.g. IS NULL)
+	//   numArgs <  0 means one placeholder per element of a slice value
+	lookup struct {
+		sql     string
+		numArgs int
+	}
+
+	// clause is a single Filter/Exclude expression, already split into its
+	// column and operator suffix.
+	clause struct {
+		col   string
+		op    string
+		not   bool
+		value interface{}
+	}
+
+	// QuerySet builds a SELECT statement incrementally through chained calls
+	// instead of bitmask fields/whereFields, translating "field__op" lookups
+	// into SQL fragments. It reuses the same Preparer/Cacher pattern as the
+	// rest of the package: once a QuerySet's condition shape has been seen,
+	// the compiled statement is reused for every later call with that shape.
+	//
+	// Example:
+	//   db.QuerySet(&User{}).
+	//   	Filter("age__gte", 18).
+	//   	Exclude("status__in", []int{2, 3}).
+	//   	OrderBy("-created").
+	//   	Limit(0, 20).
+	//   	All(&store)
+	QuerySet struct {
+		db    *DB
+		model Model
+		table string
+
+		clauses []clause
+		order   []string
+		start   int
+		count   int
+		limited bool
+
+		err error
+	}
+)
+
+// lookups maps a "__"-suffix to the SQL fragment it expands to
+var lookups = map[string]lookup{
+	"":           {"= ?", 1},
+	"gt":         {"> ?", 1},
+	"gte":        {">= ?", 1},
+	"lt":         {"< ?", 1},
+	"lte":        {"<= ?", 1},
+	"ne":         {"!= ?", 1},
+	"in":         {"IN (%s)", -1},
+	"between":    {"BETWEEN ? AND ?", 2},
+	"isnull":     {"IS NULL", 0},
+	"contains":   {"LIKE ?", 1},
+	"icontains":  {"LIKE ?", 1},
+	"startswith": {"LIKE ?", 1},
+	"endswith":   {"LIKE ?", 1},
+}
+
+// qsType is the single sql type qsCache needs, it's kept separate from the
+// package's predefined sql types since a QuerySet's cache key is already a
+// hash of its full shape rather than a (fields, whereFields) pair.
+const qsType uint = 0
+
+// QuerySet start building a query for model
+func (db *DB) QuerySet(model Model) *QuerySet {
+	return &QuerySet{
+		db:    db,
+		model: model,
+		table: model.Table(),
+	}
+}
+
+// Filter add an AND'ed condition, expr may carry a "__op" suffix such as
+// "age__gte", an expr without suffix behaves as plain equality
+func (qs *QuerySet) Filter(expr string, value interface{}) *QuerySet {
+	return qs.addClause(expr, value, false)
+}
+
+// Exclude is like Filter, but negates the condition
+func (qs *QuerySet) Exclude(expr string, value interface{}) *QuerySet {
+	return qs.addClause(expr, value, true)
+}
+
+func (qs *QuerySet) addClause(expr string, value interface{}, not bool) *QuerySet {
+	if qs.err != nil {
+		return qs
+	}
+
+	col, op := splitLookup(expr)
+	if _, has := lookups[op]; !has {
+		qs.err = fmt.Errorf("gomodel: unknown lookup operator in %q", expr)
+
+		return qs
+	}
+
+	if !qs.hasColumn(col) {
+		qs.err = fmt.Errorf("gomodel: unknown field %q for model %q", col, qs.table)
+
+		return qs
+	}
+
+	qs.clauses = append(qs.clauses, clause{col: col, op: op, not: not, value: value})
+
+	return qs
+}
+
+// hasColumn reports whether col is one of qs.model's real columns, the same
+// check Named's writeNamedField makes before splicing a field name into SQL
+func (qs *QuerySet) hasColumn(col string) bool {
+	for _, c := range qs.db.Table(qs.model).Columns() {
+		if c == col {
+			return true
+		}
+	}
+
+	return false
+}
+
+// splitLookup splits "field__op" into ("field", "op"), if the suffix after
+// the last "__" isn't a known operator, the whole expr is treated as the
+// column name with an empty (equality) operator
+func splitLookup(expr string) (col, op string) {
+	if i := strings.LastIndex(expr, "__"); i >= 0 {
+		if _, has := lookups[expr[i+2:]]; has {
+			return expr[:i], expr[i+2:]
+		}
+	}
+
+	return expr, ""
+}
+
+// OrderBy appends sort fields, prefix a field with "-" for descending order
+func (qs *QuerySet) OrderBy(fields ...string) *QuerySet {
+	if qs.err != nil {
+		return qs
+	}
+
+	for _, f := range fields {
+		if col := strings.TrimPrefix(f, "-"); !qs.hasColumn(col) {
+			qs.err = fmt.Errorf("gomodel: unknown field %q for model %q", col, qs.table)
+
+			return qs
+		}
+	}
+
+	qs.order = append(qs.order, fields...)
+
+	return qs
+}
+
+// Limit restricts the result to count rows starting at start
+func (qs *QuerySet) Limit(start, count int) *QuerySet {
+	qs.start, qs.count, qs.limited = start, count, true
+
+	return qs
+}
+
+// All runs the query and scans all matched rows into store
+func (qs *QuerySet) All(store Store) error {
+	if qs.err != nil {
+		return qs.err
+	}
+
+	dialect := qs.db.dialect
+	if dialect == nil {
+		return fmt.Errorf("gomodel: QuerySet requires a Dialect, none resolved for this DB")
+	}
+
+	bindStore(store, qs.db.Table(qs.model))
+
+	sql_, args := qs.build(dialect)
+
+	qs.db.qsMu.Lock()
+	preparedSQL, cached, stmt, err := qs.db.qsCache.StmtById(qs.db.DB, qsType, qs.id(), func() string {
+		return dialect.Rewrite(sql_)
+	})
+	qs.db.qsMu.Unlock()
+
+	scanner := QueryContext(context.Background(), preparedSQL, cached, stmt, err, args...)
+
+	return scanner.All(store, qs.db.InitialModels)
+}
+
+// shape renders the condition/order/limit structure of the QuerySet, two
+// QuerySets with the same shape always produce identical SQL text, so shape
+// is safe to hash as a prepared-statement cache key regardless of the
+// bound values
+func (qs *QuerySet) shape() string {
+	var b strings.Builder
+
+	b.WriteString(qs.table)
+	for _, c := range qs.clauses {
+		b.WriteByte('|')
+		if c.not {
+			b.WriteByte('!')
+		}
+		b.WriteString(c.col)
+		b.WriteByte(':')
+		b.WriteString(c.op)
+
+		if lookups[c.op].numArgs < 0 {
+			b.WriteByte(':')
+			b.WriteString(strconv.Itoa(sliceLen(c.value)))
+		}
+	}
+
+	b.WriteString(">o")
+	for _, f := range qs.order {
+		b.WriteByte(':')
+		b.WriteString(f)
+	}
+
+	if qs.limited {
+		b.WriteString(">l")
+	}
+
+	return b.String()
+}
+
+func (qs *QuerySet) id() uint {
+	h := fnv.New64a()
+	h.Write([]byte(qs.shape()))
+
+	return uint(h.Sum64())
+}
+
+// build renders the SQL text and flattened bound arguments for the
+// QuerySet, quoting every identifier it splices in through dialect the same
+// way Table's statement builders do
+func (qs *QuerySet) build(dialect Dialect) (string, []interface{}) {
+	where := make([]string, 0, len(qs.clauses))
+	args := make([]interface{}, 0, len(qs.clauses)+2)
+
+	for _, c := range qs.clauses {
+		l := lookups[c.op]
+		frag := l.sql
+		col := dialect.Quote(c.col)
+
+		switch {
+		case l.numArgs == 0:
+			// no bound value, e.g. IS NULL
+		case l.numArgs < 0:
+			n := sliceLen(c.value)
+			if n == 0 {
+				// An empty slice has no values to match against: "IN ()" is
+				// invalid SQL, so render the condition it actually means
+				// instead ("in" matches nothing, "not in" matches anything).
+				cond := "1=0"
+				if c.not {
+					cond = "1=1"
+				}
+				where = append(where, cond)
+
+				continue
+			}
+			frag = fmt.Sprintf(frag, OnlyParamed(n))
+			args = append(args, flatten(c.value)...)
+		default:
+			args = append(args, lookupArgs(c)...)
+		}
+
+		if c.op == "icontains" {
+			// case-insensitive on any collation, not just the ones where the
+			// column happens to already be case-insensitive
+			col = "LOWER(" + col + ")"
+		}
+
+		if c.not {
+			where = append(where, fmt.Sprintf("NOT (%s %s)", col, frag))
+		} else {
+			where = append(where, fmt.Sprintf("%s %s", col, frag))
+		}
+	}
+
+	sql_ := "SELECT * FROM " + dialect.Quote(qs.table)
+	if len(where) != 0 {
+		sql_ += " WHERE " + strings.Join(where, " AND ")
+	}
+	if len(qs.order) != 0 {
+		sql_ += " ORDER BY " + orderBy(dialect, qs.order)
+	}
+	if qs.limited {
+		sql_ += " " + limitPlaceholders(dialect)
+		args = append(args, qs.start, qs.count)
+	}
+
+	return sql_, args
+}
+
+// lookupArgs turns a clause's raw value into the bound arguments its
+// operator expects, rewriting contains/startswith/endswith to LIKE patterns
+func lookupArgs(c clause) []interface{} {
+	switch c.op {
+	case "contains":
+		return []interface{}{"%" + fmt.Sprint(c.value) + "%"}
+	case "icontains":
+		return []interface{}{"%" + strings.ToLower(fmt.Sprint(c.value)) + "%"}
+	case "startswith":
+		return []interface{}{fmt.Sprint(c.value) + "%"}
+	case "endswith":
+		return []interface{}{"%" + fmt.Sprint(c.value)}
+	case "between":
+		return flatten(c.value)
+	default:
+		return []interface{}{c.value}
+	}
+}
+
+func orderBy(dialect Dialect, fields []string) string {
+	parts := make([]string, len(fields))
+	for i, f := range fields {
+		if strings.HasPrefix(f, "-") {
+			parts[i] = dialect.Quote(f[1:]) + " DESC"
+		} else {
+			parts[i] = dialect.Quote(f) + " ASC"
+		}
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+func sliceLen(v interface{}) int {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		return rv.Len()
+	}
+
+	return 1
+}
+
+func flatten(v interface{}) []interface{} {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return []interface{}{v}
+	}
+
+	vals := make([]interface{}, rv.Len())
+	for i := range vals {
+		vals[i] = rv.Index(i).Interface()
+	}
+
+	return vals
+}
@@ -0,0 +1,110 @@
+package gomodel
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitLookup(t *testing.T) {
+	cases := []struct {
+		expr    string
+		col, op string
+	}{
+		{"age", "age", ""},
+		{"age__gte", "age", "gte"},
+		{"status__in", "status", "in"},
+		{"weird__notalookup", "weird__notalookup", ""},
+	}
+
+	for _, c := range cases {
+		col, op := splitLookup(c.expr)
+		if col != c.col || op != c.op {
+			t.Errorf("splitLookup(%q) = (%q, %q), want (%q, %q)", c.expr, col, op, c.col, c.op)
+		}
+	}
+}
+
+func TestSliceLen(t *testing.T) {
+	if n := sliceLen([]int{1, 2, 3}); n != 3 {
+		t.Fatalf("sliceLen(slice) = %d, want 3", n)
+	}
+	if n := sliceLen(42); n != 1 {
+		t.Fatalf("sliceLen(scalar) = %d, want 1", n)
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	got := flatten([]int{1, 2, 3})
+	want := []interface{}{1, 2, 3}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("flatten(slice) = %v, want %v", got, want)
+	}
+
+	if got := flatten(42); !reflect.DeepEqual(got, []interface{}{42}) {
+		t.Fatalf("flatten(scalar) = %v, want [42]", got)
+	}
+}
+
+func TestOrderByQuotesAndDirects(t *testing.T) {
+	got := orderBy(mysqlDialect{}, []string{"-created", "name"})
+	want := "`created` DESC, `name` ASC"
+	if got != want {
+		t.Fatalf("orderBy() = %q, want %q", got, want)
+	}
+}
+
+func TestLookupArgsIcontainsLowersValue(t *testing.T) {
+	args := lookupArgs(clause{op: "icontains", value: "AliCe"})
+	if len(args) != 1 || args[0] != "%alice%" {
+		t.Fatalf("lookupArgs(icontains) = %v, want [%%alice%%]", args)
+	}
+}
+
+func TestLookupArgsContainsKeepsCase(t *testing.T) {
+	args := lookupArgs(clause{op: "contains", value: "AliCe"})
+	if len(args) != 1 || args[0] != "%AliCe%" {
+		t.Fatalf("lookupArgs(contains) = %v, want [%%AliCe%%]", args)
+	}
+}
+
+func TestQuerySetBuildIcontainsWrapsColumnInLower(t *testing.T) {
+	qs := &QuerySet{
+		table:   "users",
+		clauses: []clause{{col: "name", op: "icontains", value: "Bob"}},
+	}
+
+	sql_, args := qs.build(mysqlDialect{})
+
+	const want = "SELECT * FROM `users` WHERE LOWER(`name`) LIKE ?"
+	if sql_ != want {
+		t.Fatalf("build() sql = %q, want %q", sql_, want)
+	}
+	if len(args) != 1 || args[0] != "%bob%" {
+		t.Fatalf("build() args = %v, want [%%bob%%]", args)
+	}
+}
+
+func TestQuerySetBuildLimitUsesDialectPlaceholders(t *testing.T) {
+	qs := &QuerySet{
+		table:   "users",
+		limited: true,
+		start:   10,
+		count:   20,
+	}
+
+	mysqlSQL, mysqlArgs := qs.build(mysqlDialect{})
+	if want := "SELECT * FROM `users` LIMIT ?,?"; mysqlSQL != want {
+		t.Fatalf("build() mysql sql = %q, want %q", mysqlSQL, want)
+	}
+
+	pgSQL, pgArgs := qs.build(postgresDialect{})
+	if want := `SELECT * FROM "users" OFFSET ? LIMIT ?`; pgSQL != want {
+		t.Fatalf("build() postgres sql = %q, want %q", pgSQL, want)
+	}
+
+	for _, args := range [][]interface{}{mysqlArgs, pgArgs} {
+		if len(args) != 2 || args[0] != 10 || args[1] != 20 {
+			t.Fatalf("build() args = %v, want [10 20]", args)
+		}
+	}
+}
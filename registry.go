@@ -0,0 +1,52 @@
+package gomodel
+
+import (
+	"fmt"
+	"sync"
+)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]*DB)
+)
+
+// Register opens a new database connection and keeps it reachable by alias
+// through Use, so a process can work against several named connections
+// (e.g. "default", "reports") at once.
+//
+// Example:
+//   gomodel.Register("default", "mysql", dsn, 4, 16)
+//   gomodel.Register("reports", "postgres", reportsDSN, 4, 16)
+//
+//   gomodel.Use("reports").Table(model)
+func Register(alias, driver, dsn string, maxIdle, maxOpen int) (*DB, error) {
+	if _, err := dialectFor(driver); err != nil {
+		return nil, err
+	}
+
+	db := NewDB()
+	if err := db.Connect(driver, dsn, maxIdle, maxOpen); err != nil {
+		return nil, err
+	}
+
+	registryMu.Lock()
+	registry[alias] = db
+	registryMu.Unlock()
+
+	return db, nil
+}
+
+// Use returns the database registered under alias by Register, it panics if
+// alias was never registered since it's normally called with a constant
+// alias known ahead of time
+func Use(alias string) *DB {
+	registryMu.RLock()
+	db, has := registry[alias]
+	registryMu.RUnlock()
+
+	if !has {
+		panic(fmt.Sprintf("gomodel: database %q is not registered", alias))
+	}
+
+	return db
+}
@@ -0,0 +1,190 @@
+package gomodel
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+type (
+	// TableBinder is an optional interface for Store, implemented by stores
+	// whose row-to-field mapping depends on a Model's Table, such as the
+	// one returned by StructStore. DB binds the Table right before handing
+	// the Store to a Scanner.
+	TableBinder interface {
+		bindTable(t *Table)
+	}
+
+	// structStore is a reflection-based Store that scans rows directly into
+	// a slice of structs, so callers don't have to hand-write a Model's
+	// Vals/Ptrs/FieldPtrs just to read results back. Columns are matched
+	// against `db:"col"` struct tags, falling back to the lowercased field
+	// name, against the Table's columns; the mapping is resolved once per
+	// element type and column list, and then cached.
+	structStore struct {
+		slice reflect.Value // the *[]T target, dereferenced
+		elem  reflect.Type
+
+		mapping []int // result column index -> struct field index, -1 if unmapped
+	}
+)
+
+// StructStore wraps slicePtr, a pointer to a slice of structs, as a Store
+// that scans query results directly into it via reflection.
+//
+// Example:
+//   var users []User
+//   db.All(gomodel.StructStore(&users), &User{}, AllFields, 0)
+func StructStore(slicePtr interface{}) *structStore {
+	v := reflect.ValueOf(slicePtr)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Slice {
+		panic("gomodel: StructStore requires a pointer to a slice of structs")
+	}
+
+	return &structStore{
+		slice: v.Elem(),
+		elem:  v.Elem().Type().Elem(),
+	}
+}
+
+func (s *structStore) bindTable(t *Table) {
+	s.mapping = fieldMapping(s.elem, t.Columns())
+}
+
+func (s *structStore) Init(size int) {
+	if s.slice.Cap() < size {
+		s.slice.Set(reflect.MakeSlice(s.slice.Type(), size, size))
+	} else {
+		s.slice.SetLen(size)
+	}
+}
+
+func (s *structStore) Final(size int) {
+	s.slice.SetLen(size)
+}
+
+func (s *structStore) Ptrs(index int, ptrs []interface{}) {
+	elem := s.slice.Index(index)
+
+	for i, fieldIdx := range s.mapping {
+		if fieldIdx < 0 {
+			var discard interface{}
+			ptrs[i] = &discard
+
+			continue
+		}
+
+		ptrs[i] = elem.Field(fieldIdx).Addr().Interface()
+	}
+}
+
+func (s *structStore) Realloc(count int) int {
+	size := count * 2
+	grown := reflect.MakeSlice(s.slice.Type(), size, size)
+	reflect.Copy(grown, s.slice)
+	s.slice.Set(grown)
+
+	return size
+}
+
+// fieldMappingKey identifies a cached mapping, the same element type may be
+// bound against different column lists
+type fieldMappingKey struct {
+	elem    reflect.Type
+	columns string
+}
+
+var fieldMappingCache = struct {
+	sync.RWMutex
+	m map[fieldMappingKey][]int
+}{m: make(map[fieldMappingKey][]int)}
+
+// fieldMapping resolves, and caches, which struct field each of columns maps
+// to for elem, -1 marks a column with no matching field
+func fieldMapping(elem reflect.Type, columns []string) []int {
+	key := fieldMappingKey{elem: elem, columns: strings.Join(columns, "\x00")}
+
+	fieldMappingCache.RLock()
+	mapping, has := fieldMappingCache.m[key]
+	fieldMappingCache.RUnlock()
+	if has {
+		return mapping
+	}
+
+	byName := make(map[string]int, elem.NumField())
+	for i := 0; i < elem.NumField(); i++ {
+		byName[dbColumnName(elem.Field(i))] = i
+	}
+
+	mapping = make([]int, len(columns))
+	for i, col := range columns {
+		if idx, has := byName[col]; has {
+			mapping[i] = idx
+		} else {
+			mapping[i] = -1
+		}
+	}
+
+	fieldMappingCache.Lock()
+	fieldMappingCache.m[key] = mapping
+	fieldMappingCache.Unlock()
+
+	return mapping
+}
+
+// dbColumnName is the column a struct field maps to: its "db" tag, or its
+// lowercased name if the tag is absent. SyncSchema and BulkInsert reuse it
+// so a model's column naming stays consistent across every reflection-based
+// feature in the package.
+func dbColumnName(field reflect.StructField) string {
+	if name := field.Tag.Get("db"); name != "" {
+		return name
+	}
+
+	return strings.ToLower(field.Name)
+}
+
+// MapStore drains sc into a slice of column-name -> value maps, one per row.
+// Unlike StructStore it needs no registered Model, making it a fit for
+// ad-hoc QueryById/Named queries whose result shape isn't known ahead of
+// time.
+func MapStore(sc Scanner, initsize int) ([]map[string]interface{}, error) {
+	if sc.Error != nil {
+		return nil, sc.Error
+	}
+	defer sc.Close()
+
+	rows := sc.Rows
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	if initsize <= 0 {
+		initsize = 10
+	}
+
+	result := make([]map[string]interface{}, 0, initsize)
+	for rows.Next() {
+		vals := make([]interface{}, len(cols))
+		ptrs := make([]interface{}, len(cols))
+		for i := range vals {
+			ptrs[i] = &vals[i]
+		}
+
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, err
+		}
+
+		row := make(map[string]interface{}, len(cols))
+		for i, col := range cols {
+			row[col] = vals[i]
+		}
+
+		result = append(result, row)
+	}
+
+	return result, rows.Err()
+}
@@ -0,0 +1,22 @@
+package gomodel
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDbColumnName(t *testing.T) {
+	type row struct {
+		Tagged   string `db:"custom_name"`
+		Untagged string
+	}
+
+	typ := reflect.TypeOf(row{})
+
+	if got := dbColumnName(typ.Field(0)); got != "custom_name" {
+		t.Fatalf("dbColumnName(tagged) = %q, want %q", got, "custom_name")
+	}
+	if got := dbColumnName(typ.Field(1)); got != "untagged" {
+		t.Fatalf("dbColumnName(untagged) = %q, want %q", got, "untagged")
+	}
+}
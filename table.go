@@ -0,0 +1,200 @@
+package gomodel
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Table holds a registered Model's resolved table name and column list, and
+// caches the prepared statements Insert/Update/Delete/One/Limit/All/Count/
+// IncrBy build for it. Every one of those statements is generated through
+// the owning DB's Dialect, so the same Model produces correct SQL no matter
+// which backend it was registered against.
+type Table struct {
+	db   *DB
+	Name string
+
+	columns []string
+	cache   Cacher
+}
+
+// parse builds the Table for model against db, resolving its column names
+// the same way SyncSchema and BulkInsert do: a field's "db" tag, or its
+// lowercased name, in struct field declaration order. That order is the
+// same one fields/whereFields bitmasks index into.
+func parse(model Model, db *DB) *Table {
+	v := reflect.ValueOf(model)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	typ := v.Type()
+
+	cols := make([]string, typ.NumField())
+	for i := range cols {
+		cols[i] = dbColumnName(typ.Field(i))
+	}
+
+	return &Table{
+		db:      db,
+		Name:    model.Table(),
+		columns: cols,
+		cache:   NewCacher(Types),
+	}
+}
+
+// Columns returns the table's column names, in struct field declaration
+// order.
+func (t *Table) Columns() []string {
+	return t.columns
+}
+
+// cols returns the columns fields selects, in bit order
+func (t *Table) cols(fields uint64) []string {
+	cols := make([]string, 0, len(t.columns))
+	for i, c := range t.columns {
+		if fields&(1<<uint(i)) != 0 {
+			cols = append(cols, c)
+		}
+	}
+
+	return cols
+}
+
+func (t *Table) quotedCols(dialect Dialect, fields uint64) []string {
+	cols := t.cols(fields)
+	quoted := make([]string, len(cols))
+	for i, c := range cols {
+		quoted[i] = dialect.Quote(c)
+	}
+
+	return quoted
+}
+
+// where renders a "WHERE col = ? AND ..." clause for whereFields, empty if
+// whereFields selects no column
+func (t *Table) where(dialect Dialect, whereFields uint64) string {
+	cols := t.quotedCols(dialect, whereFields)
+	if len(cols) == 0 {
+		return ""
+	}
+
+	parts := make([]string, len(cols))
+	for i, c := range cols {
+		parts[i] = c + " = ?"
+	}
+
+	return " WHERE " + strings.Join(parts, " AND ")
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
+
+// stmt resolves dialect for t, guards against it being unset the same way
+// SyncSchema and Named do, and routes build's '?'-placeholder SQL through
+// Dialect.Rewrite before handing it to t's Cacher
+func (t *Table) stmt(p Preparer, typ uint, fields, whereFields uint64, build func(Dialect) string) (string, bool, *sql.Stmt, error) {
+	dialect := t.db.dialect
+	if dialect == nil {
+		return "", false, nil, fmt.Errorf("gomodel: table %q requires a Dialect, none resolved for this DB", t.Name)
+	}
+
+	id := uint(fields<<uint(len(t.columns)) | whereFields)
+
+	return t.cache.StmtById(p, typ, id, func() string {
+		return dialect.Rewrite(build(dialect))
+	})
+}
+
+func (t *Table) StmtInsert(p Preparer, fields uint64) (string, bool, *sql.Stmt, error) {
+	return t.stmt(p, INSERT, fields, 0, func(dialect Dialect) string {
+		cols := t.quotedCols(dialect, fields)
+
+		return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+			dialect.Quote(t.Name), strings.Join(cols, ", "), placeholders(len(cols)))
+	})
+}
+
+func (t *Table) StmtUpdate(p Preparer, fields, whereFields uint64) (string, bool, *sql.Stmt, error) {
+	return t.stmt(p, UPDATE, fields, whereFields, func(dialect Dialect) string {
+		cols := t.quotedCols(dialect, fields)
+		sets := make([]string, len(cols))
+		for i, c := range cols {
+			sets[i] = c + " = ?"
+		}
+
+		return fmt.Sprintf("UPDATE %s SET %s%s", dialect.Quote(t.Name), strings.Join(sets, ", "), t.where(dialect, whereFields))
+	})
+}
+
+func (t *Table) StmtDelete(p Preparer, whereFields uint64) (string, bool, *sql.Stmt, error) {
+	return t.stmt(p, DELETE, 0, whereFields, func(dialect Dialect) string {
+		return fmt.Sprintf("DELETE FROM %s%s", dialect.Quote(t.Name), t.where(dialect, whereFields))
+	})
+}
+
+func (t *Table) StmtOne(p Preparer, fields, whereFields uint64) (string, bool, *sql.Stmt, error) {
+	return t.stmt(p, SELECT_ONE, fields, whereFields, func(dialect Dialect) string {
+		cols := t.quotedCols(dialect, fields)
+
+		return fmt.Sprintf("SELECT %s FROM %s%s %s",
+			strings.Join(cols, ", "), dialect.Quote(t.Name), t.where(dialect, whereFields), dialect.Limit(0, 1))
+	})
+}
+
+func (t *Table) StmtAll(p Preparer, fields, whereFields uint64) (string, bool, *sql.Stmt, error) {
+	return t.stmt(p, SELECT_ALL, fields, whereFields, func(dialect Dialect) string {
+		cols := t.quotedCols(dialect, fields)
+
+		return fmt.Sprintf("SELECT %s FROM %s%s", strings.Join(cols, ", "), dialect.Quote(t.Name), t.where(dialect, whereFields))
+	})
+}
+
+// StmtLimit selects fields with whereFields, bound with two trailing
+// placeholders for (start, count) in that order, the fixed order
+// ArgsLimitContext always appends them in. Since start/count vary per call
+// while the statement is cached by (fields, whereFields) alone,
+// dialect.Limit's literal-offset rendering can't be used here; see
+// limitPlaceholders.
+func (t *Table) StmtLimit(p Preparer, fields, whereFields uint64) (string, bool, *sql.Stmt, error) {
+	return t.stmt(p, SELECT_LIMIT, fields, whereFields, func(dialect Dialect) string {
+		cols := t.quotedCols(dialect, fields)
+
+		return fmt.Sprintf("SELECT %s FROM %s%s %s",
+			strings.Join(cols, ", "), dialect.Quote(t.Name), t.where(dialect, whereFields), limitPlaceholders(dialect))
+	})
+}
+
+// limitPlaceholders renders a two-placeholder LIMIT clause that binds
+// (start, count) in that order. Mysql and sqlite3 both accept the
+// "LIMIT start,count" comma form; postgres doesn't, so it gets
+// "OFFSET start LIMIT count" instead, which postgres accepts in either
+// order.
+func limitPlaceholders(dialect Dialect) string {
+	if _, is := dialect.(postgresDialect); is {
+		return "OFFSET ? LIMIT ?"
+	}
+
+	return "LIMIT ?,?"
+}
+
+func (t *Table) StmtCount(p Preparer, whereFields uint64) (string, bool, *sql.Stmt, error) {
+	return t.stmt(p, COUNT, 0, whereFields, func(dialect Dialect) string {
+		return fmt.Sprintf("SELECT COUNT(*) FROM %s%s", dialect.Quote(t.Name), t.where(dialect, whereFields))
+	})
+}
+
+func (t *Table) StmtIncrBy(p Preparer, field, whereFields uint64) (string, bool, *sql.Stmt, error) {
+	cols := t.cols(field)
+	if len(cols) != 1 {
+		return "", false, nil, fmt.Errorf("gomodel: IncrBy on table %q needs exactly one field, got %d", t.Name, len(cols))
+	}
+
+	return t.stmt(p, INCRBY, field, whereFields, func(dialect Dialect) string {
+		col := dialect.Quote(cols[0])
+
+		return fmt.Sprintf("UPDATE %s SET %s = %s + ?%s", dialect.Quote(t.Name), col, col, t.where(dialect, whereFields))
+	})
+}